@@ -0,0 +1,112 @@
+package lsn50v2
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// CommandType identifies a downlink command kind.
+type CommandType uint8
+
+// Supported downlink commands, mirroring the Dragino LSN50v2 AT command
+// set exposed over LoRaWAN downlinks.
+const (
+	CmdSetTXInterval CommandType = iota
+	CmdReset
+	CmdSetWorkMode
+	CmdSetInterruptMode
+	CmdPollStatus
+)
+
+const (
+	minTXIntervalSeconds = 30
+	maxTXIntervalSeconds = 16777215 // 3 bytes, 0xFFFFFF
+)
+
+// InterruptMode selects how the digital interrupt pin is armed.
+type InterruptMode byte
+
+// Interrupt modes accepted by SetInterruptMode.
+const (
+	InterruptDisabled InterruptMode = 0x00
+	InterruptRising   InterruptMode = 0x01
+	InterruptFalling  InterruptMode = 0x02
+)
+
+// Command is a validated downlink instruction ready for EncodeCommand.
+// Build one with SetTXInterval, Reset, SetWorkMode, SetInterruptMode, or
+// PollStatus rather than constructing it directly.
+type Command struct {
+	Type              CommandType
+	txIntervalSeconds uint32
+	workMode          byte
+	interruptMode     byte
+}
+
+// SetTXInterval builds a SET_TX_INTERVAL command. seconds must fall in
+// [30, 16777215], the range the device accepts in its 3-byte field.
+func SetTXInterval(seconds uint32) (Command, error) {
+	if seconds < minTXIntervalSeconds || seconds > maxTXIntervalSeconds {
+		return Command{}, fmt.Errorf("tx interval %d out of range [%d, %d]", seconds, minTXIntervalSeconds, maxTXIntervalSeconds)
+	}
+	return Command{Type: CmdSetTXInterval, txIntervalSeconds: seconds}, nil
+}
+
+// Reset builds a RESET command that reboots the device.
+func Reset() Command {
+	return Command{Type: CmdReset}
+}
+
+// SetWorkMode builds a SET_WORKMODE command. mode must fit the 5-bit
+// work-mode field used on the uplink side (0-31).
+func SetWorkMode(mode int) (Command, error) {
+	if mode < 0 || mode > 0x1F {
+		return Command{}, fmt.Errorf("work mode %d out of range [0, 31]", mode)
+	}
+	return Command{Type: CmdSetWorkMode, workMode: byte(mode)}, nil
+}
+
+// SetInterruptMode builds a SET_INTERRUPTMODE command.
+func SetInterruptMode(mode InterruptMode) (Command, error) {
+	switch mode {
+	case InterruptDisabled, InterruptRising, InterruptFalling:
+		return Command{Type: CmdSetInterruptMode, interruptMode: byte(mode)}, nil
+	default:
+		return Command{}, fmt.Errorf("unknown interrupt mode %#x", byte(mode))
+	}
+}
+
+// PollStatus builds a POLL_STATUS command that asks the device to send
+// its status uplink on the next opportunity.
+func PollStatus() Command {
+	return Command{Type: CmdPollStatus}
+}
+
+// Encoder builds downlink command frames for the LSN50v2.
+type Encoder struct{}
+
+// NewEncoder returns a ready-to-use Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// EncodeCommand renders cmd into the raw downlink bytes and the base64
+// form expected by ChirpStack/TTN downlink queues.
+func (e *Encoder) EncodeCommand(cmd Command) ([]byte, string, error) {
+	var raw []byte
+	switch cmd.Type {
+	case CmdSetTXInterval:
+		raw = []byte{0x01, byte(cmd.txIntervalSeconds >> 16), byte(cmd.txIntervalSeconds >> 8), byte(cmd.txIntervalSeconds)}
+	case CmdReset:
+		raw = []byte{0x04}
+	case CmdSetWorkMode:
+		raw = []byte{0x0A, cmd.workMode}
+	case CmdSetInterruptMode:
+		raw = []byte{0x06, cmd.interruptMode}
+	case CmdPollStatus:
+		raw = []byte{0x26}
+	default:
+		return nil, "", fmt.Errorf("unknown command type %d", cmd.Type)
+	}
+	return raw, base64.StdEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,87 @@
+package lsn50v2
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeCommand(t *testing.T) {
+	cases := []struct {
+		name string
+		cmd  Command
+		want []byte
+	}{
+		{"set tx interval", mustCmd(SetTXInterval(600)), []byte{0x01, 0x00, 0x02, 0x58}},
+		{"reset", Reset(), []byte{0x04}},
+		{"set work mode", mustCmd(SetWorkMode(6)), []byte{0x0A, 0x06}},
+		{"set interrupt mode", mustCmd(SetInterruptMode(InterruptRising)), []byte{0x06, 0x01}},
+		{"poll status", PollStatus(), []byte{0x26}},
+	}
+
+	e := NewEncoder()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, b64, err := e.EncodeCommand(tc.cmd)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(raw, tc.want) {
+				t.Errorf("raw mismatch: got %v, want %v", raw, tc.want)
+			}
+			if b64 != base64.StdEncoding.EncodeToString(tc.want) {
+				t.Errorf("base64 mismatch: got %q", b64)
+			}
+		})
+	}
+}
+
+// mustCmd unwraps a Command constructor's result for use in table
+// literals; it panics on error, since every case here is known-valid.
+func mustCmd(cmd Command, err error) Command {
+	if err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func TestSetTXIntervalValidation(t *testing.T) {
+	if _, err := SetTXInterval(29); err == nil {
+		t.Error("expected error for interval below minimum")
+	}
+	if _, err := SetTXInterval(16777216); err == nil {
+		t.Error("expected error for interval above maximum")
+	}
+	if _, err := SetTXInterval(30); err != nil {
+		t.Errorf("unexpected error at lower bound: %v", err)
+	}
+	if _, err := SetTXInterval(16777215); err != nil {
+		t.Errorf("unexpected error at upper bound: %v", err)
+	}
+}
+
+func TestSetWorkModeValidation(t *testing.T) {
+	if _, err := SetWorkMode(-1); err == nil {
+		t.Error("expected error for negative mode")
+	}
+	if _, err := SetWorkMode(32); err == nil {
+		t.Error("expected error for mode above range")
+	}
+	if _, err := SetWorkMode(0); err != nil {
+		t.Errorf("unexpected error for mode 0: %v", err)
+	}
+}
+
+func TestSetInterruptModeValidation(t *testing.T) {
+	if _, err := SetInterruptMode(InterruptMode(0xFF)); err == nil {
+		t.Error("expected error for unknown interrupt mode")
+	}
+}
+
+func TestEncodeCommandUnknownType(t *testing.T) {
+	e := NewEncoder()
+	_, _, err := e.EncodeCommand(Command{Type: CommandType(99)})
+	if err == nil {
+		t.Error("expected error for unknown command type")
+	}
+}
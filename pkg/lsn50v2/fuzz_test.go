@@ -0,0 +1,151 @@
+package lsn50v2
+
+import (
+	"math"
+	"testing"
+)
+
+// FuzzDecode feeds arbitrary base64 strings into Decoder.Decode and
+// checks that it never panics and never hands back a NaN or ±Inf
+// measurement, however malformed the input.
+func FuzzDecode(f *testing.F) {
+	f.Add("AAAAAAAAAAA=")
+	f.Add("not-base64!!")
+	f.Add("")
+	d := NewDecoder()
+	f.Fuzz(func(t *testing.T, b64 string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Decode panicked on %q: %v", b64, r)
+			}
+		}()
+		m, err := d.Decode(b64)
+		if err != nil {
+			return
+		}
+		for _, meas := range m {
+			if math.IsNaN(meas.Value) || math.IsInf(meas.Value, 0) {
+				t.Fatalf("Decode(%q) produced non-finite measurement %+v", b64, meas)
+			}
+		}
+	})
+}
+
+// schemaForMode looks up a mode's ModeSchema for use by the round-trip
+// test helpers below.
+func schemaForMode(mode int) ModeSchema {
+	for _, s := range modeSchemas {
+		if s.Mode == mode {
+			return s
+		}
+	}
+	panic("no schema registered for mode")
+}
+
+// setField writes value into raw at the location f describes, inverting
+// FieldSpec.decode's div and big-endian packing.
+func setField(raw []byte, f FieldSpec, value float64) {
+	div := f.Div
+	if div == 0 {
+		div = 1
+	}
+	iv := int64(math.Round(value * div))
+	for i := f.Size - 1; i >= 0; i-- {
+		raw[f.Offset+i] = byte(iv)
+		iv >>= 8
+	}
+}
+
+// encodeMode builds a raw payload for mode from a well-formed set of
+// measurements, inverting modeSchemas' Fields and the illum/SHT/weight
+// Post rules. It exists only to round-trip test Decode.
+func encodeMode(mode int, m []Measurement) []byte {
+	schema := schemaForMode(mode)
+	raw := make([]byte, schema.MinLen)
+	raw[6] = byte(mode << 2)
+
+	values := make(map[string]float64, len(m))
+	for _, meas := range m {
+		values[meas.Name] = meas.Value
+	}
+	for _, f := range schema.Fields {
+		if v, ok := values[f.Name]; ok {
+			setField(raw, f, v)
+		}
+	}
+
+	switch mode {
+	case 0, 2:
+		if v, ok := values["Illum"]; ok {
+			raw[9], raw[10] = 0x00, 0x00 // selects the illum branch
+			setField(raw, FieldSpec{Offset: 7, Size: 2, Div: 1}, v)
+			break
+		}
+		// Non-zero, non-sentinel placeholder so the SHT branch is taken.
+		raw[9], raw[10] = 0x00, 0x01
+		if v, ok := values["TempC SHT"]; ok {
+			setField(raw, FieldSpec{Offset: 7, Size: 2, Signed: true, Div: 10}, v)
+		}
+		if v, ok := values["Hum SHT"]; ok {
+			setField(raw, FieldSpec{Offset: 9, Size: 2, Div: 10}, v)
+		}
+		// mode4's Weight is deliberately not handled here: it would have
+		// to re-derive the same interleaved byte order mode4Post uses
+		// to decode it, which would make the round trip tautological
+		// (see the comment on mode4Post in schema.go). There's no
+		// captured real-device mode4 payload available to assert
+		// against instead, so that layout stays documented as
+		// suspicious rather than "verified" by a test that can only
+		// ever pass.
+	}
+	return raw
+}
+
+// approxEqual reports whether got and want agree within the scale
+// tolerance of the smallest step these fields are quantized to.
+func approxEqual(t *testing.T, mode int, got, want []Measurement) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("mode %d: measurement count mismatch: got %v, want %v", mode, got, want)
+	}
+	for i := range want {
+		if got[i].Name != want[i].Name {
+			t.Fatalf("mode %d: measurement[%d] name = %q, want %q", mode, i, got[i].Name, want[i].Name)
+		}
+		if math.Abs(got[i].Value-want[i].Value) > 1e-6 {
+			t.Errorf("mode %d: %s round-trip mismatch: got %v, want %v", mode, got[i].Name, got[i].Value, want[i].Value)
+		}
+	}
+}
+
+// TestRoundTripModes round-trips a well-formed payload for each mode
+// through encodeMode and back through Decode, catching sign-extension
+// and endianness bugs a one-directional unit test would miss.
+func TestRoundTripModes(t *testing.T) {
+	d := NewDecoder()
+	cases := []struct {
+		mode int
+		m    []Measurement
+	}{
+		{1, []Measurement{{"Bat V", 3.6}, {"Temp C1", 21.5}, {"ADC CH0V", 1.2}, {"Distance Cm", 123.4}, {"Signal", 42}}},
+		{3, []Measurement{{"Bat V", 3.3}, {"Temp C1", -5.2}, {"ADC CH0V", 0.5}, {"Temp C2", 18.0}, {"Temp C3", -3.1}}},
+		{5, []Measurement{{"Count", 12345}}},
+		{7, []Measurement{{"Bat V", 3.0}, {"Temp C1", 10.0}, {"ADC CH0V", 2.0}, {"ADC CH1V", 1.1}, {"ADC CH4V", 0.9}}},
+		{8, []Measurement{{"Bat V", 3.7}, {"Temp C1", 22.2}, {"Temp C2", 19.9}, {"Temp C3", -1.0}, {"Count 1", 111}, {"Count 2", 222}}},
+		// mode4 is intentionally not covered here: its Weight layout
+		// (r[9]<<24 | r[10]<<16 | r[7]<<8 | r[8], see mode4Post in
+		// schema.go) looks suspicious, and a round trip through
+		// encodeMode would just re-derive the same formula, proving
+		// nothing about whether it matches the physical device.
+		{0, []Measurement{{"Bat V", 3.3}, {"Temp C1", 20.0}, {"ADC CH0V", 1.0}, {"TempC SHT", 23.4}, {"Hum SHT", 55.0}}},
+		{0, []Measurement{{"Bat V", 3.3}, {"Temp C1", 20.0}, {"ADC CH0V", 1.0}, {"Illum", 500}}},
+	}
+	for _, tc := range cases {
+		raw := encodeMode(tc.mode, tc.m)
+		got, err := d.Decode(encode(raw))
+		if err != nil {
+			t.Fatalf("mode %d: unexpected error: %v", tc.mode, err)
+		}
+		approxEqual(t, tc.mode, got, tc.m)
+	}
+}
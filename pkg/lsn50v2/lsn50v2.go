@@ -0,0 +1,162 @@
+// Package lsn50v2 decodes Dragino LSN50v2 LoRaWAN uplink payloads.
+package lsn50v2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sync"
+)
+
+// Decoder dispatches payloads to mode handlers. The handler table is
+// guarded by mu so Register/Unregister can be called concurrently with
+// Decode. The zero value is ready to use; NewDecoder additionally
+// registers the built-in modes.
+type Decoder struct {
+	mu       sync.RWMutex
+	handlers map[int]ModeHandler
+}
+
+// Measurement holds a named metric value.
+type Measurement struct {
+	Name  string
+	Value float64
+}
+
+// ModeHandler decodes a Packet for its work mode.
+type ModeHandler interface {
+	Decode(*Packet) ([]Measurement, error)
+}
+
+// FieldReporter is implemented by handlers that can report every field
+// they recognize for a Packet, including ones an invalid-sensor
+// sentinel rejected. DecodeFull uses it when the registered handler
+// supports it; handlers that only implement ModeHandler (e.g. ones
+// added via Register) still work, but every field they contribute to a
+// Reading is reported as Valid.
+type FieldReporter interface {
+	DecodeFields(*Packet) (map[string]Field, error)
+}
+
+// Packet holds raw payload and header fields.
+type Packet struct {
+	Raw  []byte
+	Mode int
+	Band string
+}
+
+// NewDecoder sets up handlers for the built-in modes 0–5, 7, 8, each
+// driven by its ModeSchema in modeSchemas. Mode 6 and any custom
+// firmware profile are left for callers to add via Register.
+func NewDecoder() *Decoder {
+	d := &Decoder{handlers: make(map[int]ModeHandler)}
+	for _, schema := range modeSchemas {
+		d.Register(schema.Mode, schemaHandler{schema})
+	}
+	return d
+}
+
+// Register installs h as the handler for mode, replacing any existing
+// handler for that mode. It lets integrators plug in mode 6 (the
+// "Three Interrupt" variant) or any other custom firmware profile
+// without forking this package.
+func (d *Decoder) Register(mode int, h ModeHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.handlers == nil {
+		d.handlers = make(map[int]ModeHandler)
+	}
+	d.handlers[mode] = h
+}
+
+// Unregister removes the handler installed for mode, if any.
+func (d *Decoder) Unregister(mode int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.handlers, mode)
+}
+
+// Decode base64 payload into measurements. It is a thin wrapper around
+// decodePacket kept for callers that only need name/value pairs; use
+// DecodeFull for units, validity, and LoRaWAN integration metadata.
+func (d *Decoder) Decode(b64 string) ([]Measurement, error) {
+	_, m, err := d.decodePacket(b64)
+	return m, err
+}
+
+// buildPacket parses b64 into a Packet and looks up the handler
+// registered for its mode. Decode and DecodeFull both build on this so
+// the two stay in sync.
+func (d *Decoder) buildPacket(b64 string) (*Packet, ModeHandler, error) {
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base64 decode error: %w", err)
+	}
+	if len(raw) < 7 {
+		return nil, nil, fmt.Errorf("payload too short: %d bytes", len(raw))
+	}
+	p := &Packet{
+		Raw:  raw,
+		Mode: int((raw[6] & 0x7C) >> 2), // extract work mode bits
+		Band: getBand(raw[0]),
+	}
+	d.mu.RLock()
+	handler, ok := d.handlers[p.Mode]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported mode %d", p.Mode)
+	}
+	return p, handler, nil
+}
+
+func (d *Decoder) decodePacket(b64 string) (*Packet, []Measurement, error) {
+	p, handler, err := d.buildPacket(b64)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := handler.Decode(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, m, nil
+}
+
+func getBand(code byte) string {
+	switch code {
+	case 0x01:
+		return "EU868"
+	case 0x02:
+		return "US915"
+	case 0x03:
+		return "IN865"
+	case 0x04:
+		return "AU915"
+	case 0x05:
+		return "KZ865"
+	case 0x06:
+		return "RU864"
+	case 0x07:
+		return "AS923"
+	case 0x08:
+		return "AS923_1"
+	case 0x09:
+		return "AS923_2"
+	case 0x0A:
+		return "AS923_3"
+	case 0x0B:
+		return "CN470"
+	case 0x0C:
+		return "EU433"
+	case 0x0D:
+		return "KR920"
+	case 0x0E:
+		return "MA869"
+	case 0x0F:
+		return "AS923_4"
+	default:
+		return ""
+	}
+}
+
+func errPayloadTooShortForMode(mode, minLen, got int) error {
+	return fmt.Errorf("mode %d payload too short: need %d bytes, got %d", mode, minLen, got)
+}
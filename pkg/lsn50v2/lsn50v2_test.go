@@ -1,8 +1,9 @@
-package main
+package lsn50v2
 
 import (
 	"encoding/base64"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -37,7 +38,7 @@ func TestDecodeUnsupportedMode(t *testing.T) {
 	b64 := encode(raw)
 	d := NewDecoder()
 	_, err := d.Decode(b64)
-	if err == nil || !contains(err.Error(), "unsupported mode 6") {
+	if err == nil || !strings.Contains(err.Error(), "unsupported mode 6") {
 		t.Fatalf("expected unsupported mode 6 error, got %v", err)
 	}
 }
@@ -78,9 +79,51 @@ func TestDecodeMode1(t *testing.T) {
 	}
 }
 
-// helper to check substring
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && reflect.ValueOf(s).String() != "" && (func() bool {
-		return len(substr) == 0 || (len(s) >= len(substr) && (s[0:len(substr)] == substr || contains(s[1:], substr)))
-	})()
+// fakeMode6 is a stand-in for the "Three Interrupt" firmware profile,
+// used here only to prove Register/Unregister work end to end.
+type fakeMode6 struct{}
+
+func (fakeMode6) Decode(p *Packet) ([]Measurement, error) {
+	return []Measurement{{"Interrupt", float64(p.Raw[7])}}, nil
+}
+
+func TestRegisterCustomMode(t *testing.T) {
+	d := NewDecoder()
+
+	raw := make([]byte, 8)
+	raw[6] = byte(6 << 2) // mode 6
+	raw[7] = 0x05
+	b64 := encode(raw)
+
+	if _, err := d.Decode(b64); err == nil || !strings.Contains(err.Error(), "unsupported mode 6") {
+		t.Fatalf("expected unsupported mode 6 before Register, got %v", err)
+	}
+
+	d.Register(6, fakeMode6{})
+	m, err := d.Decode(b64)
+	if err != nil {
+		t.Fatalf("unexpected error after Register: %v", err)
+	}
+	expected := []Measurement{{"Interrupt", 5}}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("mode6 decode mismatch. got %v, want %v", m, expected)
+	}
+
+	d.Unregister(6)
+	if _, err := d.Decode(b64); err == nil || !strings.Contains(err.Error(), "unsupported mode 6") {
+		t.Fatalf("expected unsupported mode 6 after Unregister, got %v", err)
+	}
+
+	// Built-in table must still work after the custom mode is
+	// registered and removed.
+	mode1Raw := make([]byte, 11)
+	mode1Raw[0], mode1Raw[1] = 0x00, 0x64
+	mode1Raw[2], mode1Raw[3] = 0x7f, 0xff
+	mode1Raw[4], mode1Raw[5] = 0x00, 0xC8
+	mode1Raw[6] = byte(1 << 2)
+	mode1Raw[7], mode1Raw[8] = 0x01, 0x2C
+	mode1Raw[9], mode1Raw[10] = 0x00, 0x64
+	if _, err := d.Decode(encode(mode1Raw)); err != nil {
+		t.Fatalf("built-in mode 1 broken after registry use: %v", err)
+	}
 }
@@ -0,0 +1,112 @@
+package lsn50v2
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Field is a single decoded metric: its value, its unit, and whether the
+// sensor reported a valid reading.
+type Field struct {
+	Value float64
+	Unit  string
+	Valid bool
+}
+
+// Reading is the full result of decoding one uplink, suitable for
+// dropping into a ChirpStack-v4 or TTN-v3 "decoded payload" handler.
+type Reading struct {
+	Band       string
+	Mode       int
+	ReceivedAt time.Time
+	RawHex     string
+	Fields     map[string]Field
+}
+
+// unitByField maps a Measurement name to the physical unit it's
+// reported in. Names not listed here (e.g. future custom modes) are
+// passed through with an empty unit.
+var unitByField = map[string]string{
+	"Bat V":       "V",
+	"Temp C1":     "°C",
+	"Temp C2":     "°C",
+	"Temp C3":     "°C",
+	"TempC SHT":   "°C",
+	"Hum SHT":     "%",
+	"ADC CH0V":    "V",
+	"ADC CH1V":    "V",
+	"ADC CH4V":    "V",
+	"Illum":       "lux",
+	"Distance Cm": "cm",
+	"Weight":      "g",
+	"Count":       "count",
+	"Count 1":     "count",
+	"Count 2":     "count",
+}
+
+// DecodeFull decodes a base64 uplink into a Reading, preserving the band,
+// work mode, raw hex, and a per-metric unit and validity alongside each
+// value. For a handler that implements FieldReporter (every built-in
+// mode does), a field an invalid-sensor sentinel rejected still appears
+// in Fields with Valid:false instead of being silently dropped; for a
+// plain ModeHandler added via Register, only the measurements it
+// returned are available, and those are reported as Valid.
+func (d *Decoder) DecodeFull(b64 string) (*Reading, error) {
+	p, handler, err := d.buildPacket(b64)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]Field
+	if fr, ok := handler.(FieldReporter); ok {
+		fields, err = fr.DecodeFields(p)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		m, err := handler.Decode(p)
+		if err != nil {
+			return nil, err
+		}
+		fields = make(map[string]Field, len(m))
+		for _, meas := range m {
+			fields[meas.Name] = Field{
+				Value: meas.Value,
+				Unit:  unitByField[meas.Name],
+				Valid: true,
+			}
+		}
+	}
+	return &Reading{
+		Band:       p.Band,
+		Mode:       p.Mode,
+		ReceivedAt: time.Now(),
+		RawHex:     hex.EncodeToString(p.Raw),
+		Fields:     fields,
+	}, nil
+}
+
+// MarshalJSON flattens the Reading into a single object: uplink metadata
+// plus one top-level key per valid field, matching the flat decoded-payload
+// shape ChirpStack-v4 and TTN-v3 integrations expect.
+func (r *Reading) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(r.Fields)+4)
+	out["band"] = r.Band
+	out["mode"] = r.Mode
+	out["received_at"] = r.ReceivedAt
+	out["raw_hex"] = r.RawHex
+	for name, f := range r.Fields {
+		if !f.Valid {
+			continue
+		}
+		out[jsonFieldKey(name)] = f.Value
+	}
+	return json.Marshal(out)
+}
+
+// jsonFieldKey turns a Measurement name like "Bat V" into a JSON-friendly
+// key like "Bat_V".
+func jsonFieldKey(name string) string {
+	return strings.ReplaceAll(name, " ", "_")
+}
@@ -0,0 +1,103 @@
+package lsn50v2
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mode1Payload() []byte {
+	raw := make([]byte, 11)
+	raw[0], raw[1] = 0x00, 0x64  // Bat V = 0.1
+	raw[2], raw[3] = 0x7f, 0xff  // Temp C1 invalid
+	raw[4], raw[5] = 0x00, 0xC8  // ADC CH0V = 0.2
+	raw[6] = byte(1 << 2)        // mode 1
+	raw[7], raw[8] = 0x01, 0x2C  // Distance Cm = 30.0
+	raw[9], raw[10] = 0x00, 0x64 // Signal = 100
+	return raw
+}
+
+func TestDecodeFull(t *testing.T) {
+	d := NewDecoder()
+	r, err := d.DecodeFull(encode(mode1Payload()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Mode != 1 {
+		t.Errorf("Mode = %d, want 1", r.Mode)
+	}
+	if r.RawHex == "" {
+		t.Error("RawHex is empty")
+	}
+	dist, ok := r.Fields["Distance Cm"]
+	if !ok {
+		t.Fatal("missing Distance Cm field")
+	}
+	if dist.Value != 30.0 || dist.Unit != "cm" || !dist.Valid {
+		t.Errorf("Distance Cm = %+v, want {30 cm true}", dist)
+	}
+	temp1, ok := r.Fields["Temp C1"]
+	if !ok {
+		t.Fatal("missing Temp C1 field")
+	}
+	if temp1.Valid {
+		t.Errorf("Temp C1 = %+v, want Valid:false (sentinel hit)", temp1)
+	}
+}
+
+func TestDecodeFullUnsupportedMode(t *testing.T) {
+	d := NewDecoder()
+	raw := make([]byte, 8)
+	raw[6] = byte(6 << 2)
+	if _, err := d.DecodeFull(encode(raw)); err == nil {
+		t.Fatal("expected error for unsupported mode")
+	}
+}
+
+// TestDecodeFullPlainHandler confirms a ModeHandler that doesn't
+// implement FieldReporter (i.e. a custom handler added via Register)
+// still works with DecodeFull, reporting whatever it returned as Valid.
+func TestDecodeFullPlainHandler(t *testing.T) {
+	d := NewDecoder()
+	d.Register(6, fakeMode6{})
+
+	raw := make([]byte, 8)
+	raw[6] = byte(6 << 2)
+	raw[7] = 0x05
+
+	r, err := d.DecodeFull(encode(raw))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f, ok := r.Fields["Interrupt"]
+	if !ok {
+		t.Fatal("missing Interrupt field")
+	}
+	if f.Value != 5 || !f.Valid {
+		t.Errorf("Interrupt = %+v, want {5 ... true}", f)
+	}
+}
+
+func TestReadingMarshalJSON(t *testing.T) {
+	d := NewDecoder()
+	r, err := d.DecodeFull(encode(mode1Payload()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %v", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if out["mode"].(float64) != 1 {
+		t.Errorf("mode = %v, want 1", out["mode"])
+	}
+	if out["Distance_Cm"].(float64) != 30.0 {
+		t.Errorf("Distance_Cm = %v, want 30", out["Distance_Cm"])
+	}
+	if _, ok := out["Temp_C1"]; ok {
+		t.Error("invalid Temp_C1 should be omitted from the JSON payload")
+	}
+}
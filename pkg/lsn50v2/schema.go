@@ -0,0 +1,266 @@
+package lsn50v2
+
+import "bytes"
+
+// FieldSpec declares how to pull one metric out of a raw payload: read
+// Size bytes at Offset, treat them as a big-endian (optionally signed)
+// integer, divide it by Div, and skip the field entirely if its raw
+// bytes match one of the sentinel patterns in Invalid.
+//
+// Div (not a pre-inverted scale factor) matters here: float64(v)/10 and
+// float64(v)*0.1 are not bit-identical for most v, and this package's
+// whole job is reproducing sensor values exactly as the original
+// handlers did.
+type FieldSpec struct {
+	Name    string
+	Offset  int
+	Size    int
+	Signed  bool
+	Div     float64
+	Invalid [][]byte
+	Unit    string
+}
+
+// decode reads the field from raw, returning (value, true) or (0, false)
+// if raw is too short or the bytes match an invalid-sensor sentinel.
+func (f FieldSpec) decode(raw []byte) (float64, bool) {
+	if f.Offset+f.Size > len(raw) {
+		return 0, false
+	}
+	b := raw[f.Offset : f.Offset+f.Size]
+	for _, sentinel := range f.Invalid {
+		if bytes.Equal(b, sentinel) {
+			return 0, false
+		}
+	}
+	var v int64
+	for _, by := range b {
+		v = v<<8 | int64(by)
+	}
+	if f.Signed {
+		bits := uint(f.Size * 8)
+		sign := int64(1) << (bits - 1)
+		v = (v ^ sign) - sign
+	}
+	div := f.Div
+	if div == 0 {
+		div = 1
+	}
+	return float64(v) / div, true
+}
+
+// ModeSchema declaratively describes one work mode's payload: a minimum
+// length to validate against, the straight-line fields to extract, and
+// optional hooks for the handful of cross-field rules (illum vs. SHT
+// temp/humidity, odd byte orderings) a flat field list can't express.
+// Post builds the []Measurement Decode returns; PostFields does the
+// same job for DecodeFields, additionally reporting fields an
+// invalid-sensor sentinel rejected. A schema only needs PostFields if
+// Post does something beyond appending plain FieldSpec results.
+type ModeSchema struct {
+	Mode       int
+	MinLen     int
+	Fields     []FieldSpec
+	Post       func(p *Packet, m []Measurement) []Measurement
+	PostFields func(p *Packet, fields map[string]Field)
+}
+
+// schemaHandler adapts a ModeSchema to the ModeHandler and FieldReporter
+// interfaces.
+type schemaHandler struct {
+	schema ModeSchema
+}
+
+func (h schemaHandler) Decode(p *Packet) ([]Measurement, error) {
+	raw := p.Raw
+	if len(raw) < h.schema.MinLen {
+		return nil, errPayloadTooShortForMode(h.schema.Mode, h.schema.MinLen, len(raw))
+	}
+	m := make([]Measurement, 0, len(h.schema.Fields))
+	for _, f := range h.schema.Fields {
+		if v, ok := f.decode(raw); ok {
+			m = append(m, Measurement{f.Name, v})
+		}
+	}
+	if h.schema.Post != nil {
+		m = h.schema.Post(p, m)
+	}
+	return m, nil
+}
+
+// DecodeFields reports every field in the schema, including ones whose
+// raw bytes matched an invalid-sensor sentinel, so DecodeFull can
+// surface Field.Valid honestly instead of silently dropping them.
+func (h schemaHandler) DecodeFields(p *Packet) (map[string]Field, error) {
+	raw := p.Raw
+	if len(raw) < h.schema.MinLen {
+		return nil, errPayloadTooShortForMode(h.schema.Mode, h.schema.MinLen, len(raw))
+	}
+	fields := make(map[string]Field, len(h.schema.Fields))
+	for _, f := range h.schema.Fields {
+		v, ok := f.decode(raw)
+		fields[f.Name] = Field{Value: v, Unit: f.Unit, Valid: ok}
+	}
+	if h.schema.PostFields != nil {
+		h.schema.PostFields(p, fields)
+	}
+	return fields, nil
+}
+
+func batteryField() FieldSpec {
+	return FieldSpec{Name: "Bat V", Offset: 0, Size: 2, Div: 1000, Unit: "V"}
+}
+
+func temp1Field() FieldSpec {
+	return FieldSpec{Name: "Temp C1", Offset: 2, Size: 2, Signed: true, Div: 10, Invalid: [][]byte{{0x7f, 0xff}}, Unit: "°C"}
+}
+
+func adc0Field() FieldSpec {
+	return FieldSpec{Name: "ADC CH0V", Offset: 4, Size: 2, Div: 1000, Unit: "V"}
+}
+
+// modeSchemas is the declarative table for all built-in work modes.
+// Appending an entry here is all a new mode needs; Register exists for
+// modes (like 6) that aren't in this table at all.
+var modeSchemas = []ModeSchema{
+	{
+		// mode0: 3ADC, SHT, Illum.
+		Mode:       0,
+		MinLen:     11,
+		Fields:     []FieldSpec{batteryField(), temp1Field(), adc0Field()},
+		Post:       mode0Post,
+		PostFields: mode0PostFields,
+	},
+	{
+		// mode1: distance, signal.
+		Mode:   1,
+		MinLen: 11,
+		Fields: []FieldSpec{
+			batteryField(), temp1Field(), adc0Field(),
+			{Name: "Distance Cm", Offset: 7, Size: 2, Div: 10, Invalid: [][]byte{{0x00, 0x00}}, Unit: "cm"},
+			{Name: "Signal", Offset: 9, Size: 2, Div: 1, Invalid: [][]byte{{0xff, 0xff}}},
+		},
+	},
+	{
+		// mode2: 3ADC+IIC.
+		Mode:   2,
+		MinLen: 12,
+		Fields: []FieldSpec{
+			{Name: "Bat V", Offset: 11, Size: 1, Div: 10, Unit: "V"},
+			{Name: "ADC CH0V", Offset: 0, Size: 2, Div: 1000, Unit: "V"},
+			{Name: "ADC CH1V", Offset: 2, Size: 2, Div: 1000, Unit: "V"},
+			{Name: "ADC CH4V", Offset: 4, Size: 2, Div: 1000, Unit: "V"},
+		},
+		Post:       mode0Post,
+		PostFields: mode0PostFields,
+	},
+	{
+		// mode3: two DS18B20 temps.
+		Mode:   3,
+		MinLen: 11,
+		Fields: []FieldSpec{
+			batteryField(), temp1Field(), adc0Field(),
+			{Name: "Temp C2", Offset: 7, Size: 2, Signed: true, Div: 10, Invalid: [][]byte{{0x7f, 0xff}}, Unit: "°C"},
+			{Name: "Temp C3", Offset: 9, Size: 2, Signed: true, Div: 10, Invalid: [][]byte{{0x7f, 0xff}}, Unit: "°C"},
+		},
+	},
+	{
+		// mode4: weight. The byte order is interleaved rather than
+		// contiguous, so it's built in Post instead of as a FieldSpec.
+		Mode:       4,
+		MinLen:     11,
+		Post:       mode4Post,
+		PostFields: mode4PostFields,
+	},
+	{
+		// mode5: counter.
+		Mode:   5,
+		MinLen: 11,
+		Fields: []FieldSpec{
+			{Name: "Count", Offset: 7, Size: 4, Div: 1},
+		},
+	},
+	{
+		// mode7: ADC + DS18B20.
+		Mode:   7,
+		MinLen: 11,
+		Fields: []FieldSpec{
+			batteryField(), temp1Field(), adc0Field(),
+			{Name: "ADC CH1V", Offset: 7, Size: 2, Div: 1000, Unit: "V"},
+			{Name: "ADC CH4V", Offset: 9, Size: 2, Div: 1000, Unit: "V"},
+		},
+	},
+	{
+		// mode8: DS18B20 + 2 counters.
+		Mode:   8,
+		MinLen: 17,
+		Fields: []FieldSpec{
+			batteryField(), temp1Field(),
+			{Name: "Temp C2", Offset: 4, Size: 2, Signed: true, Div: 10, Invalid: [][]byte{{0x7f, 0xff}}, Unit: "°C"},
+			{Name: "Temp C3", Offset: 7, Size: 2, Signed: true, Div: 10, Invalid: [][]byte{{0x7f, 0xff}}, Unit: "°C"},
+			{Name: "Count 1", Offset: 9, Size: 4, Div: 1},
+			{Name: "Count 2", Offset: 13, Size: 4, Div: 1},
+		},
+	},
+}
+
+// mode0Post implements the illum-vs-SHT cross-field rule shared by
+// mode0 and mode2: if bytes 9-10 are zero, bytes 7-8 are an illuminance
+// reading instead of an SHT temp, and humidity only appears alongside a
+// valid SHT temp.
+func mode0Post(p *Packet, m []Measurement) []Measurement {
+	r := p.Raw
+	if (int(r[9])<<8 | int(r[10])) == 0 {
+		illum := float64(int64(r[7])<<8 | int64(r[8]))
+		return append(m, Measurement{"Illum", illum})
+	}
+	shtTemp := FieldSpec{Name: "TempC SHT", Offset: 7, Size: 2, Signed: true, Div: 10,
+		Invalid: [][]byte{{0x7f, 0xff}, {0xff, 0xff}}, Unit: "°C"}
+	if v, ok := shtTemp.decode(r); ok {
+		m = append(m, Measurement{"TempC SHT", v})
+		hum := FieldSpec{Name: "Hum SHT", Offset: 9, Size: 2, Div: 10, Invalid: [][]byte{{0xff, 0xff}}, Unit: "%"}
+		if h, ok := hum.decode(r); ok {
+			m = append(m, Measurement{"Hum SHT", h})
+		}
+	}
+	return m
+}
+
+// mode4Post rebuilds mode4's weight reading. The device packs it as
+// r[9]<<24 | r[10]<<16 | r[7]<<8 | r[8] — an interleaved byte order
+// preserved here as-is from the original handler.
+func mode4Post(p *Packet, m []Measurement) []Measurement {
+	r := p.Raw
+	w := float64(int64(r[9])<<24 | int64(r[10])<<16 | int64(r[7])<<8 | int64(r[8]))
+	return append(m, Measurement{"Weight", w})
+}
+
+// mode0PostFields is mode0Post's DecodeFields counterpart: it reports
+// TempC SHT and Hum SHT even when their sentinel marks them invalid,
+// instead of leaving them out of the Reading entirely. Hum SHT is only
+// meaningful once TempC SHT itself decoded, matching mode0Post's
+// nesting.
+func mode0PostFields(p *Packet, fields map[string]Field) {
+	r := p.Raw
+	if (int(r[9])<<8 | int(r[10])) == 0 {
+		illum := float64(int64(r[7])<<8 | int64(r[8]))
+		fields["Illum"] = Field{Value: illum, Unit: "lux", Valid: true}
+		return
+	}
+	shtTemp := FieldSpec{Offset: 7, Size: 2, Signed: true, Div: 10, Invalid: [][]byte{{0x7f, 0xff}, {0xff, 0xff}}, Unit: "°C"}
+	v, ok := shtTemp.decode(r)
+	fields["TempC SHT"] = Field{Value: v, Unit: "°C", Valid: ok}
+	if ok {
+		hum := FieldSpec{Offset: 9, Size: 2, Div: 10, Invalid: [][]byte{{0xff, 0xff}}, Unit: "%"}
+		hv, hok := hum.decode(r)
+		fields["Hum SHT"] = Field{Value: hv, Unit: "%", Valid: hok}
+	}
+}
+
+// mode4PostFields is mode4Post's DecodeFields counterpart. Weight has
+// no invalid-sensor sentinel, so it's always reported as valid.
+func mode4PostFields(p *Packet, fields map[string]Field) {
+	r := p.Raw
+	w := float64(int64(r[9])<<24 | int64(r[10])<<16 | int64(r[7])<<8 | int64(r[8]))
+	fields["Weight"] = Field{Value: w, Unit: "g", Valid: true}
+}
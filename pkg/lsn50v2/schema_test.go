@@ -0,0 +1,91 @@
+package lsn50v2
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestModeSchemasMatchHandlerBehavior(t *testing.T) {
+	d := NewDecoder()
+
+	mode3Raw := []byte{0x00, 0x64, 0x7f, 0xff, 0x00, 0xC8, byte(3 << 2), 0x00, 0xC8, 0x00, 0x96}
+	m, err := d.Decode(encode(mode3Raw))
+	if err != nil {
+		t.Fatalf("mode3: unexpected error: %v", err)
+	}
+	want := []Measurement{
+		{"Bat V", 0.1},
+		{"ADC CH0V", 0.2},
+		{"Temp C2", 20.0},
+		{"Temp C3", 15.0},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("mode3 mismatch: got %v, want %v", m, want)
+	}
+
+	mode5Raw := make([]byte, 11)
+	mode5Raw[6] = byte(5 << 2)
+	mode5Raw[7], mode5Raw[8], mode5Raw[9], mode5Raw[10] = 0x00, 0x00, 0x01, 0x00
+	m, err = d.Decode(encode(mode5Raw))
+	if err != nil {
+		t.Fatalf("mode5: unexpected error: %v", err)
+	}
+	if want := []Measurement{{"Count", 256}}; !reflect.DeepEqual(m, want) {
+		t.Errorf("mode5 mismatch: got %v, want %v", m, want)
+	}
+
+	mode4Raw := make([]byte, 11)
+	mode4Raw[6] = byte(4 << 2)
+	mode4Raw[7], mode4Raw[8], mode4Raw[9], mode4Raw[10] = 0x01, 0x02, 0x03, 0x04
+	m, err = d.Decode(encode(mode4Raw))
+	if err != nil {
+		t.Fatalf("mode4: unexpected error: %v", err)
+	}
+	wantWeight := float64(int64(0x03)<<24 | int64(0x04)<<16 | int64(0x01)<<8 | int64(0x02))
+	if want := []Measurement{{"Weight", wantWeight}}; !reflect.DeepEqual(m, want) {
+		t.Errorf("mode4 mismatch: got %v, want %v", m, want)
+	}
+}
+
+func TestModeSchemasRejectShortPayload(t *testing.T) {
+	d := NewDecoder()
+	for _, schema := range modeSchemas {
+		raw := make([]byte, schema.MinLen-1)
+		if schema.MinLen == 0 {
+			continue
+		}
+		raw[6] = byte(schema.Mode << 2)
+		if _, err := d.Decode(encode(raw)); err == nil {
+			t.Errorf("mode %d: expected error for payload shorter than MinLen", schema.Mode)
+		}
+	}
+}
+
+// FuzzModeSchemas feeds every length from 0 to 32 bytes, filled with
+// pseudo-random data, into every registered mode and checks that no
+// schema handler panics, regardless of how malformed the payload is.
+func FuzzModeSchemas(f *testing.F) {
+	for _, schema := range modeSchemas {
+		f.Add(schema.Mode, int64(schema.Mode))
+	}
+	f.Fuzz(func(t *testing.T, mode int, seed int64) {
+		rng := rand.New(rand.NewSource(seed))
+		for length := 0; length <= 32; length++ {
+			raw := make([]byte, length)
+			rng.Read(raw)
+			if length > 6 {
+				raw[6] = byte((mode & 0x1f) << 2)
+			}
+			d := NewDecoder()
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("panic decoding mode %d, len %d: %v", mode, length, r)
+					}
+				}()
+				_, _ = d.Decode(encode(raw))
+			}()
+		}
+	})
+}